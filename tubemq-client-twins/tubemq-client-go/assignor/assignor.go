@@ -0,0 +1,101 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package assignor implements the pluggable partition assignment strategies
+// a consumer group uses to divide topic partitions among its members,
+// mirroring the assignor abstraction of the wider Kafka client ecosystem.
+package assignor
+
+import (
+	"sort"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+)
+
+// Assignor divides the partitions of a set of topics among the subscribing
+// consumers of a group.
+type Assignor interface {
+	// Name returns the strategy name, as used by
+	// config.Consumer.PartitionAssignmentStrategy.
+	Name() string
+	// Assign returns the partitions to assign to each consumerID in
+	// subscribers. partitionsByTopic holds every partition of every
+	// subscribed topic. currentAssignment is the assignment in effect
+	// before this rebalance, keyed by consumerID; it is nil on a group's
+	// first assignment and may be used by strategies that try to
+	// minimize partition movement.
+	Assign(partitionsByTopic map[string][]*metadata.Partition, subscribers []string, currentAssignment map[string][]*metadata.Partition) map[string][]*metadata.Partition
+}
+
+// New returns the Assignor registered under strategy, one of
+// config.RangeAssignor, config.RoundRobinAssignor or config.StickyAssignor.
+// It returns nil if strategy is not recognized.
+//
+// Nothing in this tree calls New yet: there is no client/consumer_impl.go
+// to read config.Consumer.PartitionAssignmentStrategy and drive a rebalance
+// with the result. Wiring it in belongs with whichever change adds that
+// consumer group implementation.
+func New(strategy string) Assignor {
+	switch strategy {
+	case config.RangeAssignor:
+		return &RangeAssignor{}
+	case config.RoundRobinAssignor:
+		return &RoundRobinAssignor{}
+	case config.StickyAssignor:
+		return &StickyAssignor{}
+	default:
+		return nil
+	}
+}
+
+// sortedPartitions returns every partition of every topic, sorted by topic
+// then by partition key, so that strategies produce a deterministic
+// assignment given the same inputs.
+func sortedPartitions(partitionsByTopic map[string][]*metadata.Partition) []*metadata.Partition {
+	topics := make([]string, 0, len(partitionsByTopic))
+	for topic := range partitionsByTopic {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	partitions := make([]*metadata.Partition, 0)
+	for _, topic := range topics {
+		topicPartitions := append([]*metadata.Partition(nil), partitionsByTopic[topic]...)
+		sort.Slice(topicPartitions, func(i, j int) bool {
+			return topicPartitions[i].GetPartitionKey() < topicPartitions[j].GetPartitionKey()
+		})
+		partitions = append(partitions, topicPartitions...)
+	}
+	return partitions
+}
+
+// sortedSubscribers returns subscribers sorted so that strategies produce a
+// deterministic assignment given the same inputs.
+func sortedSubscribers(subscribers []string) []string {
+	sorted := append([]string(nil), subscribers...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// sortByPartitionKey sorts partitions in place by partition key, so that
+// strategies produce a deterministic assignment given the same inputs.
+func sortByPartitionKey(partitions []*metadata.Partition) {
+	sort.Slice(partitions, func(i, j int) bool {
+		return partitions[i].GetPartitionKey() < partitions[j].GetPartitionKey()
+	})
+}
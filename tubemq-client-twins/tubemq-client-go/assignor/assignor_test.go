@@ -0,0 +1,97 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assignor
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPartition(topic, partitionKey string) *metadata.Partition {
+	p := &metadata.Partition{}
+	p.SetTopic(topic)
+	p.SetPartitionKey(partitionKey)
+	return p
+}
+
+func partitionsByTopic(topic string, n int) map[string][]*metadata.Partition {
+	partitions := make([]*metadata.Partition, 0, n)
+	for i := 0; i < n; i++ {
+		partitions = append(partitions, newTestPartition(topic, topic+"#"+string(rune('0'+i))))
+	}
+	return map[string][]*metadata.Partition{topic: partitions}
+}
+
+func TestNewReturnsRegisteredAssignors(t *testing.T) {
+	assert.Equal(t, config.RangeAssignor, New(config.RangeAssignor).Name())
+	assert.Equal(t, config.RoundRobinAssignor, New(config.RoundRobinAssignor).Name())
+	assert.Equal(t, config.StickyAssignor, New(config.StickyAssignor).Name())
+	assert.Nil(t, New("unknown"))
+}
+
+func TestRangeAssignorEvenSplit(t *testing.T) {
+	a := &RangeAssignor{}
+	result := a.Assign(partitionsByTopic("topic", 4), []string{"c1", "c2"}, nil)
+	assert.Len(t, result["c1"], 2)
+	assert.Len(t, result["c2"], 2)
+}
+
+func TestRoundRobinAssignorDistributesEvenly(t *testing.T) {
+	a := &RoundRobinAssignor{}
+	result := a.Assign(partitionsByTopic("topic", 5), []string{"c1", "c2"}, nil)
+	assert.Len(t, result["c1"], 3)
+	assert.Len(t, result["c2"], 2)
+}
+
+func TestStickyAssignorKeepsPreviousPartitionsWhenCompatible(t *testing.T) {
+	a := &StickyAssignor{}
+	byTopic := partitionsByTopic("topic", 4)
+	current := map[string][]*metadata.Partition{
+		"c1": {byTopic["topic"][0], byTopic["topic"][1]},
+		"c2": {byTopic["topic"][2], byTopic["topic"][3]},
+	}
+
+	result := a.Assign(byTopic, []string{"c1", "c2"}, current)
+	assert.ElementsMatch(t, current["c1"], result["c1"])
+	assert.ElementsMatch(t, current["c2"], result["c2"])
+}
+
+func TestStickyAssignorOnlyReassignsTheDelta(t *testing.T) {
+	a := &StickyAssignor{}
+	byTopic := partitionsByTopic("topic", 4)
+	current := map[string][]*metadata.Partition{
+		"c1": {byTopic["topic"][0], byTopic["topic"][1], byTopic["topic"][2], byTopic["topic"][3]},
+	}
+
+	result := a.Assign(byTopic, []string{"c1", "c2"}, current)
+	assert.Len(t, result["c1"], 2)
+	assert.Len(t, result["c2"], 2)
+	// c1 must keep two of the partitions it already had.
+	kept := 0
+	for _, p := range result["c1"] {
+		for _, prev := range current["c1"] {
+			if p == prev {
+				kept++
+			}
+		}
+	}
+	assert.Equal(t, 2, kept)
+}
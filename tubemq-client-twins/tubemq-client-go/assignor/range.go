@@ -0,0 +1,69 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assignor
+
+import (
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+)
+
+// RangeAssignor divides each topic's partitions into contiguous ranges, one
+// range per consumer, so that a consumer's partitions for a topic are
+// adjacent to one another.
+type RangeAssignor struct{}
+
+// Name returns config.RangeAssignor.
+func (a *RangeAssignor) Name() string {
+	return config.RangeAssignor
+}
+
+// Assign implements Assignor.
+func (a *RangeAssignor) Assign(partitionsByTopic map[string][]*metadata.Partition, subscribers []string, currentAssignment map[string][]*metadata.Partition) map[string][]*metadata.Partition {
+	assignment := make(map[string][]*metadata.Partition, len(subscribers))
+	if len(subscribers) == 0 {
+		return assignment
+	}
+	subscribers = sortedSubscribers(subscribers)
+	for _, consumerID := range subscribers {
+		assignment[consumerID] = make([]*metadata.Partition, 0)
+	}
+
+	for _, partitions := range partitionsByTopic {
+		sorted := append([]*metadata.Partition(nil), partitions...)
+		sortByPartitionKey(sorted)
+
+		numPartitions := len(sorted)
+		numConsumers := len(subscribers)
+		partitionsPerConsumer := numPartitions / numConsumers
+		partitionsWithExtra := numPartitions % numConsumers
+
+		start := 0
+		for i, consumerID := range subscribers {
+			length := partitionsPerConsumer
+			if i < partitionsWithExtra {
+				length++
+			}
+			if length == 0 {
+				continue
+			}
+			assignment[consumerID] = append(assignment[consumerID], sorted[start:start+length]...)
+			start += length
+		}
+	}
+	return assignment
+}
@@ -0,0 +1,52 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assignor
+
+import (
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+)
+
+// RoundRobinAssignor deals out every subscribed partition, across all
+// topics, to the consumers in turn, favoring an even total partition count
+// per consumer over locality within a topic.
+type RoundRobinAssignor struct{}
+
+// Name returns config.RoundRobinAssignor.
+func (a *RoundRobinAssignor) Name() string {
+	return config.RoundRobinAssignor
+}
+
+// Assign implements Assignor.
+func (a *RoundRobinAssignor) Assign(partitionsByTopic map[string][]*metadata.Partition, subscribers []string, currentAssignment map[string][]*metadata.Partition) map[string][]*metadata.Partition {
+	assignment := make(map[string][]*metadata.Partition, len(subscribers))
+	if len(subscribers) == 0 {
+		return assignment
+	}
+	subscribers = sortedSubscribers(subscribers)
+	for _, consumerID := range subscribers {
+		assignment[consumerID] = make([]*metadata.Partition, 0)
+	}
+
+	partitions := sortedPartitions(partitionsByTopic)
+	for i, partition := range partitions {
+		consumerID := subscribers[i%len(subscribers)]
+		assignment[consumerID] = append(assignment[consumerID], partition)
+	}
+	return assignment
+}
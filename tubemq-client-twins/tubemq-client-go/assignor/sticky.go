@@ -0,0 +1,110 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package assignor
+
+import (
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+)
+
+// StickyAssignor balances partitions as evenly as RoundRobinAssignor, but
+// minimizes partition movement across rebalances: a consumer keeps as many
+// of its previously assigned partitions as its new target count allows, and
+// only the delta is redistributed. This matters for stateful consumers,
+// where losing a partition means discarding local state built up for it.
+type StickyAssignor struct{}
+
+// Name returns config.StickyAssignor.
+func (a *StickyAssignor) Name() string {
+	return config.StickyAssignor
+}
+
+// Assign implements Assignor.
+func (a *StickyAssignor) Assign(partitionsByTopic map[string][]*metadata.Partition, subscribers []string, currentAssignment map[string][]*metadata.Partition) map[string][]*metadata.Partition {
+	assignment := make(map[string][]*metadata.Partition, len(subscribers))
+	if len(subscribers) == 0 {
+		return assignment
+	}
+	subscribers = sortedSubscribers(subscribers)
+	for _, consumerID := range subscribers {
+		assignment[consumerID] = make([]*metadata.Partition, 0)
+	}
+
+	allPartitions := sortedPartitions(partitionsByTopic)
+	byKey := make(map[string]*metadata.Partition, len(allPartitions))
+	for _, partition := range allPartitions {
+		byKey[partition.GetPartitionKey()] = partition
+	}
+
+	targetCount := targetPartitionCounts(len(allPartitions), subscribers)
+
+	assigned := make(map[string]bool, len(allPartitions))
+	for _, consumerID := range subscribers {
+		prev := append([]*metadata.Partition(nil), currentAssignment[consumerID]...)
+		sortByPartitionKey(prev)
+		for _, partition := range prev {
+			if len(assignment[consumerID]) >= targetCount[consumerID] {
+				break
+			}
+			key := partition.GetPartitionKey()
+			actual, stillValid := byKey[key]
+			if !stillValid || assigned[key] {
+				continue
+			}
+			assignment[consumerID] = append(assignment[consumerID], actual)
+			assigned[key] = true
+		}
+	}
+
+	unassigned := make([]*metadata.Partition, 0, len(allPartitions))
+	for _, partition := range allPartitions {
+		if !assigned[partition.GetPartitionKey()] {
+			unassigned = append(unassigned, partition)
+		}
+	}
+
+	next := 0
+	for _, partition := range unassigned {
+		for tries := 0; tries < len(subscribers); tries++ {
+			consumerID := subscribers[next%len(subscribers)]
+			next++
+			if len(assignment[consumerID]) < targetCount[consumerID] {
+				assignment[consumerID] = append(assignment[consumerID], partition)
+				break
+			}
+		}
+	}
+	return assignment
+}
+
+// targetPartitionCounts spreads numPartitions as evenly as possible across
+// subscribers, handing the remainder to the first few in sorted order.
+func targetPartitionCounts(numPartitions int, subscribers []string) map[string]int {
+	numConsumers := len(subscribers)
+	perConsumer := numPartitions / numConsumers
+	extra := numPartitions % numConsumers
+
+	target := make(map[string]int, numConsumers)
+	for i, consumerID := range subscribers {
+		target[consumerID] = perConsumer
+		if i < extra {
+			target[consumerID]++
+		}
+	}
+	return target
+}
@@ -0,0 +1,153 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package auth implements the SASL authentication handshake the transport
+// performs right after the TLS handshake and before the client's first
+// heartbeat/register request.
+//
+// Nothing in this tree dials that transport yet: there is no connection or
+// transport/dial file that opens a TLS-wrapped socket to a broker/master and
+// calls Negotiate on it before the first heartbeat/register request.
+// config.ParseAddress parses config.Net.TLS.Enable; config.Net.SASL is
+// set on the Config directly, since ParseAddress reads no SASL parameter.
+// This package's handshake logic is tested against the Transport interface
+// only.
+package auth
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/codec"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+)
+
+// Mechanism is a client-side SASL mechanism driver. The handshake is a
+// challenge/response exchange: the client is asked for a token with a nil
+// challenge first, then answers however many further challenges the server
+// sends until Done reports the exchange complete.
+type Mechanism interface {
+	// Name returns the mechanism name advertised during the handshake,
+	// e.g. "PLAIN" or "SCRAM-SHA-256".
+	Name() string
+	// Step advances the exchange, consuming the server's challenge (nil
+	// on the first call) and returning the client's response.
+	Step(challenge []byte) (response []byte, err error)
+	// Done reports whether the exchange is complete.
+	Done() bool
+}
+
+// NewMechanism returns the Mechanism configured by sasl.
+func NewMechanism(sasl config.SASL) (Mechanism, error) {
+	switch sasl.Mechanism {
+	case config.SASLTypePlain:
+		return NewPlainMechanism(sasl.Username, sasl.Password), nil
+	case config.SASLTypeSCRAMSHA256:
+		return NewScramMechanism(config.SASLTypeSCRAMSHA256, ScramSHA256, sasl.Username, sasl.Password)
+	case config.SASLTypeSCRAMSHA512:
+		return NewScramMechanism(config.SASLTypeSCRAMSHA512, ScramSHA512, sasl.Username, sasl.Password)
+	default:
+		return nil, fmt.Errorf("auth: unsupported SASL mechanism %q", sasl.Mechanism)
+	}
+}
+
+// Transport sends a SASL request body and returns the matching response
+// body. No type in this tree implements it outside tests; Negotiate
+// depends on this interface, not a concrete connection, so it can be
+// tested without dialing a broker.
+type Transport interface {
+	Send(body []byte) error
+	Receive() ([]byte, error)
+}
+
+// Negotiate performs the SASL handshake over transport using the mechanism
+// configured in sasl. It is a no-op if sasl.Enable is false. It fails the
+// connection if the broker's advertised mechanisms do not include the
+// configured one.
+func Negotiate(transport Transport, sasl config.SASL) error {
+	if !sasl.Enable {
+		return nil
+	}
+	mechanism, err := NewMechanism(sasl)
+	if err != nil {
+		return err
+	}
+
+	resp, err := roundTrip(transport, &codec.SASLRequest{
+		Opcode:    codec.OpcodeSASLHandshake,
+		Mechanism: mechanism.Name(),
+	})
+	if err != nil {
+		return err
+	}
+	if resp.ErrorCode != 0 {
+		return fmt.Errorf("auth: handshake rejected: %s", resp.ErrMsg)
+	}
+	if !mechanismEnabled(resp.EnabledMechanisms, mechanism.Name()) {
+		return fmt.Errorf("auth: server does not support mechanism %q, has %v", mechanism.Name(), resp.EnabledMechanisms)
+	}
+
+	var challenge []byte
+	for {
+		token, err := mechanism.Step(challenge)
+		if err != nil {
+			return err
+		}
+		resp, err = roundTrip(transport, &codec.SASLRequest{
+			Opcode: codec.OpcodeSASLHandshake,
+			Token:  token,
+		})
+		if err != nil {
+			return err
+		}
+		if resp.ErrorCode != 0 {
+			return fmt.Errorf("auth: authentication failed: %s", resp.ErrMsg)
+		}
+		if resp.Done || mechanism.Done() {
+			if scram, ok := mechanism.(*ScramMechanism); ok {
+				if err := scram.VerifyServerSignature(resp.Token); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		challenge = resp.Token
+	}
+}
+
+func roundTrip(transport Transport, req *codec.SASLRequest) (*codec.SASLResponse, error) {
+	body, err := codec.EncodeSASLRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := transport.Send(body); err != nil {
+		return nil, err
+	}
+	respBody, err := transport.Receive()
+	if err != nil {
+		return nil, err
+	}
+	return codec.DecodeSASLResponse(respBody)
+}
+
+func mechanismEnabled(enabled []string, name string) bool {
+	for _, m := range enabled {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/codec"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+)
+
+// fakeTransport drives Negotiate against a canned sequence of responses,
+// recording every request sent so tests can assert on them.
+type fakeTransport struct {
+	responses [][]byte
+	requests  []*codec.SASLRequest
+}
+
+func (t *fakeTransport) Send(body []byte) error {
+	req := &codec.SASLRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return err
+	}
+	t.requests = append(t.requests, req)
+	return nil
+}
+
+func (t *fakeTransport) Receive() ([]byte, error) {
+	resp := t.responses[0]
+	t.responses = t.responses[1:]
+	return resp, nil
+}
+
+func TestNegotiateNoopWhenDisabled(t *testing.T) {
+	transport := &fakeTransport{}
+	err := Negotiate(transport, config.SASL{Enable: false})
+	assert.Nil(t, err)
+	assert.Empty(t, transport.requests)
+}
+
+func TestNegotiatePlainSucceeds(t *testing.T) {
+	transport := &fakeTransport{
+		responses: [][]byte{
+			mustEncodeResponse(&codec.SASLResponse{EnabledMechanisms: []string{"PLAIN"}}),
+			mustEncodeResponse(&codec.SASLResponse{Done: true}),
+		},
+	}
+	sasl := config.SASL{Enable: true, Mechanism: config.SASLTypePlain, Username: "user", Password: "pass"}
+
+	err := Negotiate(transport, sasl)
+	assert.Nil(t, err)
+	assert.Len(t, transport.requests, 2)
+	assert.Equal(t, "PLAIN", transport.requests[0].Mechanism)
+	assert.Equal(t, "\x00user\x00pass", string(transport.requests[1].Token))
+}
+
+func TestNegotiateRejectsUnadvertisedMechanism(t *testing.T) {
+	transport := &fakeTransport{
+		responses: [][]byte{
+			mustEncodeResponse(&codec.SASLResponse{EnabledMechanisms: []string{"SCRAM-SHA-256"}}),
+		},
+	}
+	sasl := config.SASL{Enable: true, Mechanism: config.SASLTypePlain, Username: "user", Password: "pass"}
+
+	err := Negotiate(transport, sasl)
+	assert.NotNil(t, err)
+}
+
+func TestNegotiateFailsOnErrorResponse(t *testing.T) {
+	transport := &fakeTransport{
+		responses: [][]byte{
+			mustEncodeResponse(&codec.SASLResponse{ErrorCode: 1, ErrMsg: "denied"}),
+		},
+	}
+	sasl := config.SASL{Enable: true, Mechanism: config.SASLTypePlain, Username: "user", Password: "pass"}
+
+	err := Negotiate(transport, sasl)
+	assert.NotNil(t, err)
+}
+
+// scramTransport drives Negotiate against a scramServer instead of a canned
+// response queue, since the SCRAM exchange's later messages depend on the
+// client's earlier ones. forgeSignature stands in for a relay/impersonating
+// broker: it lets a valid client-verified exchange still ship a
+// server-final-message the client did not actually derive.
+type scramTransport struct {
+	server         *scramServer
+	enabled        []string
+	forgeSignature bool
+	pending        []byte
+	clientFirst    []byte
+}
+
+func (t *scramTransport) Send(body []byte) error {
+	req := &codec.SASLRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return err
+	}
+	switch {
+	case req.Mechanism != "":
+		t.pending = mustEncodeResponse(&codec.SASLResponse{EnabledMechanisms: t.enabled})
+	case t.clientFirst == nil:
+		t.clientFirst = req.Token
+		t.pending = mustEncodeResponse(&codec.SASLResponse{Token: t.server.firstResponse(req.Token)})
+	default:
+		serverFinal, ok := t.server.finalResponse(req.Token)
+		if !ok {
+			t.pending = mustEncodeResponse(&codec.SASLResponse{ErrorCode: 1, ErrMsg: "authentication failed"})
+			return nil
+		}
+		if t.forgeSignature {
+			serverFinal = []byte("v=" + base64.StdEncoding.EncodeToString([]byte("not-the-real-signature")))
+		}
+		t.pending = mustEncodeResponse(&codec.SASLResponse{Token: serverFinal, Done: true})
+	}
+	return nil
+}
+
+func (t *scramTransport) Receive() ([]byte, error) {
+	return t.pending, nil
+}
+
+func TestNegotiateScramVerifiesServerSignature(t *testing.T) {
+	transport := &scramTransport{
+		server:  newScramServer(ScramSHA256, "user", "pass"),
+		enabled: []string{"SCRAM-SHA-256"},
+	}
+	sasl := config.SASL{Enable: true, Mechanism: config.SASLTypeSCRAMSHA256, Username: "user", Password: "pass"}
+
+	err := Negotiate(transport, sasl)
+	assert.Nil(t, err)
+}
+
+func TestNegotiateScramRejectsForgedServerSignature(t *testing.T) {
+	transport := &scramTransport{
+		server:         newScramServer(ScramSHA256, "user", "pass"),
+		enabled:        []string{"SCRAM-SHA-256"},
+		forgeSignature: true,
+	}
+	sasl := config.SASL{Enable: true, Mechanism: config.SASLTypeSCRAMSHA256, Username: "user", Password: "pass"}
+
+	err := Negotiate(transport, sasl)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "server signature mismatch")
+}
+
+func mustEncodeResponse(resp *codec.SASLResponse) []byte {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
@@ -0,0 +1,59 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"errors"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+)
+
+// PlainMechanism implements RFC 4616 SASL PLAIN: a single message carrying
+// the authorization identity (left empty), the username and the password,
+// each separated by a NUL byte.
+type PlainMechanism struct {
+	username string
+	password string
+	done     bool
+}
+
+// NewPlainMechanism returns a Mechanism authenticating as username with
+// password.
+func NewPlainMechanism(username, password string) *PlainMechanism {
+	return &PlainMechanism{username: username, password: password}
+}
+
+// Name returns config.SASLTypePlain.
+func (m *PlainMechanism) Name() string {
+	return string(config.SASLTypePlain)
+}
+
+// Step implements Mechanism. PLAIN is a single message with no server
+// challenge, so it ignores its argument and can only be called once.
+func (m *PlainMechanism) Step(challenge []byte) ([]byte, error) {
+	if m.done {
+		return nil, errors.New("auth: PLAIN exchange already complete")
+	}
+	m.done = true
+	return []byte("\x00" + m.username + "\x00" + m.password), nil
+}
+
+// Done implements Mechanism.
+func (m *PlainMechanism) Done() bool {
+	return m.done
+}
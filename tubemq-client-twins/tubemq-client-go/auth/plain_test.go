@@ -0,0 +1,38 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainMechanismStep(t *testing.T) {
+	m := NewPlainMechanism("user", "pass")
+	assert.Equal(t, "PLAIN", m.Name())
+	assert.False(t, m.Done())
+
+	token, err := m.Step(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "\x00user\x00pass", string(token))
+	assert.True(t, m.Done())
+
+	_, err = m.Step(nil)
+	assert.NotNil(t, err)
+}
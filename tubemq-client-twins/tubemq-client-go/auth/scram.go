@@ -0,0 +1,240 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/config"
+)
+
+// ScramHashFunc selects the hash algorithm a ScramMechanism uses, as
+// required by RFC 5802.
+type ScramHashFunc func() hash.Hash
+
+// ScramSHA256 and ScramSHA512 are the hash functions backing
+// config.SASLTypeSCRAMSHA256 and config.SASLTypeSCRAMSHA512.
+var (
+	ScramSHA256 ScramHashFunc = sha256.New
+	ScramSHA512 ScramHashFunc = sha512.New
+)
+
+// scramStep tracks where a ScramMechanism is in the three-message exchange
+// RFC 5802 defines: client-first, client-final, then done.
+type scramStep int
+
+const (
+	scramStepClientFirst scramStep = iota
+	scramStepClientFinal
+	scramStepDone
+)
+
+// ScramMechanism implements RFC 5802 SCRAM (Salted Challenge Response
+// Authentication Mechanism) for SASL.
+type ScramMechanism struct {
+	name     string
+	hashFunc ScramHashFunc
+	username string
+	password string
+	nonce    string
+
+	step            scramStep
+	clientFirstBare string
+	serverFirstMsg  string
+	saltedPassword  []byte
+	authMessage     string
+}
+
+// NewScramMechanism returns a Mechanism authenticating as username with
+// password, hashing with hashFunc. name is the mechanism name advertised
+// during the handshake, e.g. config.SASLTypeSCRAMSHA256.
+func NewScramMechanism(name config.SASLMechanism, hashFunc ScramHashFunc, username, password string) (*ScramMechanism, error) {
+	nonce, err := scramNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &ScramMechanism{
+		name:     string(name),
+		hashFunc: hashFunc,
+		username: username,
+		password: password,
+		nonce:    nonce,
+	}, nil
+}
+
+func scramNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(buf), nil
+}
+
+// Name returns the mechanism name NewScramMechanism was constructed with.
+func (m *ScramMechanism) Name() string {
+	return m.name
+}
+
+// Step implements Mechanism.
+func (m *ScramMechanism) Step(challenge []byte) ([]byte, error) {
+	switch m.step {
+	case scramStepClientFirst:
+		m.step = scramStepClientFinal
+		return m.clientFirstMessage(), nil
+	case scramStepClientFinal:
+		msg, err := m.clientFinalMessage(challenge)
+		if err != nil {
+			return nil, err
+		}
+		m.step = scramStepDone
+		return msg, nil
+	default:
+		return nil, errors.New("auth: SCRAM exchange already complete")
+	}
+}
+
+// Done implements Mechanism.
+func (m *ScramMechanism) Done() bool {
+	return m.step == scramStepDone
+}
+
+// clientFirstMessage builds the "client-first-message" of RFC 5802 section
+// 5, using the "n" GS2 header, meaning no channel binding.
+func (m *ScramMechanism) clientFirstMessage() []byte {
+	m.clientFirstBare = fmt.Sprintf("n=%s,r=%s", scramEscape(m.username), m.nonce)
+	return []byte("n,," + m.clientFirstBare)
+}
+
+// clientFinalMessage builds the "client-final-message" of RFC 5802 section
+// 5 in response to the server's "server-first-message".
+func (m *ScramMechanism) clientFinalMessage(serverFirstMessage []byte) ([]byte, error) {
+	m.serverFirstMsg = string(serverFirstMessage)
+	fields, err := parseScramMessage(m.serverFirstMsg)
+	if err != nil {
+		return nil, err
+	}
+	serverNonce, salt, iterations := fields["r"], fields["s"], fields["i"]
+	if serverNonce == "" || salt == "" || iterations == "" {
+		return nil, fmt.Errorf("auth: malformed SCRAM server-first-message %q", m.serverFirstMsg)
+	}
+	if !strings.HasPrefix(serverNonce, m.nonce) {
+		return nil, errors.New("auth: SCRAM server nonce does not extend the client nonce")
+	}
+	iterCount, err := strconv.Atoi(iterations)
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed SCRAM iteration count %q", iterations)
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("auth: malformed SCRAM salt: %w", err)
+	}
+
+	h := m.hashFunc
+	m.saltedPassword = pbkdf2.Key([]byte(m.password), saltBytes, iterCount, h().Size(), h)
+
+	clientFinalWithoutProof := fmt.Sprintf("c=biws,r=%s", serverNonce)
+	m.authMessage = strings.Join([]string{m.clientFirstBare, m.serverFirstMsg, clientFinalWithoutProof}, ",")
+
+	clientKey := hmacSum(h, m.saltedPassword, []byte("Client Key"))
+	storedKey := hashSum(h, clientKey)
+	clientSignature := hmacSum(h, storedKey, []byte(m.authMessage))
+	clientProof := xorBytes(clientKey, clientSignature)
+
+	msg := fmt.Sprintf("%s,p=%s", clientFinalWithoutProof, base64.StdEncoding.EncodeToString(clientProof))
+	return []byte(msg), nil
+}
+
+// VerifyServerSignature checks the "v=" server-final-message field TubeMQ
+// sends once it has accepted the exchange, proving it also knows the
+// password (or at least the server key derived from it) rather than just
+// relaying it.
+func (m *ScramMechanism) VerifyServerSignature(serverFinalMessage []byte) error {
+	fields, err := parseScramMessage(string(serverFinalMessage))
+	if err != nil {
+		return err
+	}
+	v, ok := fields["v"]
+	if !ok {
+		return fmt.Errorf("auth: malformed SCRAM server-final-message %q", serverFinalMessage)
+	}
+	serverSignature, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return fmt.Errorf("auth: malformed SCRAM server signature: %w", err)
+	}
+
+	h := m.hashFunc
+	serverKey := hmacSum(h, m.saltedPassword, []byte("Server Key"))
+	expected := hmacSum(h, serverKey, []byte(m.authMessage))
+	if !hmac.Equal(expected, serverSignature) {
+		return errors.New("auth: SCRAM server signature mismatch")
+	}
+	return nil
+}
+
+func hmacSum(h ScramHashFunc, key, data []byte) []byte {
+	mac := hmac.New(h, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func hashSum(h ScramHashFunc, data []byte) []byte {
+	sum := h()
+	sum.Write(data)
+	return sum.Sum(nil)
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// scramEscape applies the RFC 5802 section 5.1 "saslprep"-adjacent escaping
+// of the two characters with meaning in the attribute-value syntax.
+func scramEscape(s string) string {
+	s = strings.ReplaceAll(s, "=", "=3D")
+	s = strings.ReplaceAll(s, ",", "=2C")
+	return s
+}
+
+// parseScramMessage splits a comma-separated "k=v" SCRAM message into a map
+// keyed by k.
+func parseScramMessage(msg string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for _, part := range strings.Split(msg, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("auth: malformed SCRAM message field %q", part)
+		}
+		fields[kv[0]] = kv[1]
+	}
+	return fields, nil
+}
@@ -0,0 +1,114 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package client defines the Consumer interface applications use to talk
+// to TubeMQ, and implements the offset-seek support on top of it.
+package client
+
+import (
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/remote"
+)
+
+// Consumer is the interface implemented by a TubeMQ consumer.
+type Consumer interface {
+	// SeekToOffset repositions the given partition to offset, so the
+	// next GetMessage call for it returns the message stored there.
+	SeekToOffset(partitionKey string, offset int64) error
+	// SeekToTimestamp repositions the given partition to the start of the
+	// first segment last updated at or after tsMillis. The lookup works at
+	// segment granularity, so the messages that follow can include ones
+	// stored in that segment before tsMillis.
+	SeekToTimestamp(partitionKey string, tsMillis int64) error
+	// SeekToBeginning repositions the given partition to its earliest
+	// available offset.
+	SeekToBeginning(partitionKey string) error
+	// SeekToEnd repositions the given partition to its latest available
+	// offset.
+	SeekToEnd(partitionKey string) error
+}
+
+// OffsetResetter issues the reset-offset RPC that repositions a partition
+// on the broker side. No type in this tree implements it outside tests;
+// seeking depends on this interface, not a concrete client, so it can be
+// tested without a live broker connection.
+type OffsetResetter interface {
+	ResetOffset(partitionKey string, offset int64) error
+}
+
+// SegmentIndexSource returns the segment index TubeMQ maintains for
+// partitionKey, used to translate a timestamp or SeekToEnd into an offset.
+type SegmentIndexSource interface {
+	SegmentIndex(partitionKey string) []remote.SegmentIndexEntry
+}
+
+// seeker implements the seek half of Consumer on top of an RmtDataCache, an
+// OffsetResetter, and a SegmentIndexSource, so that a Consumer
+// implementation can embed it rather than reimplement offset translation.
+// No Consumer implementation in this tree embeds it.
+type seeker struct {
+	rmtDataCache *remote.RmtDataCache
+	resetter     OffsetResetter
+	segmentIndex SegmentIndexSource
+}
+
+// newSeeker returns a seeker driving rmtDataCache, issuing reset-offset
+// RPCs through resetter and resolving timestamps through segmentIndex.
+func newSeeker(rmtDataCache *remote.RmtDataCache, resetter OffsetResetter, segmentIndex SegmentIndexSource) *seeker {
+	return &seeker{
+		rmtDataCache: rmtDataCache,
+		resetter:     resetter,
+		segmentIndex: segmentIndex,
+	}
+}
+
+// SeekToOffset implements Consumer.
+func (s *seeker) SeekToOffset(partitionKey string, offset int64) error {
+	return s.seek(partitionKey, offset)
+}
+
+// SeekToTimestamp implements Consumer.
+func (s *seeker) SeekToTimestamp(partitionKey string, tsMillis int64) error {
+	offset := remote.SearchOffsetByTimestamp(s.segmentIndex.SegmentIndex(partitionKey), tsMillis)
+	return s.seek(partitionKey, offset)
+}
+
+// SeekToBeginning implements Consumer.
+func (s *seeker) SeekToBeginning(partitionKey string) error {
+	return s.seek(partitionKey, 0)
+}
+
+// SeekToEnd implements Consumer.
+func (s *seeker) SeekToEnd(partitionKey string) error {
+	index := s.segmentIndex.SegmentIndex(partitionKey)
+	if len(index) == 0 {
+		return s.seek(partitionKey, 0)
+	}
+	return s.seek(partitionKey, index[len(index)-1].MaxOffset)
+}
+
+// seek pauses partitionKey in the RmtDataCache, issues the reset-offset RPC
+// for offset, and resumes the partition once the broker has acked it. The
+// partition stays paused, and therefore ineligible for GetMessage
+// selection, if the RPC fails.
+func (s *seeker) seek(partitionKey string, offset int64) error {
+	s.rmtDataCache.PausePartition(partitionKey)
+	if err := s.resetter.ResetOffset(partitionKey, offset); err != nil {
+		return err
+	}
+	s.rmtDataCache.ResumePartition(partitionKey)
+	return nil
+}
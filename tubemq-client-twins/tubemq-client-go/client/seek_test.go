@@ -0,0 +1,99 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/remote"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResetter struct {
+	resetPartitionKey string
+	resetOffset       int64
+	err               error
+}
+
+func (f *fakeResetter) ResetOffset(partitionKey string, offset int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.resetPartitionKey = partitionKey
+	f.resetOffset = offset
+	return nil
+}
+
+type fakeSegmentIndexSource struct {
+	index []remote.SegmentIndexEntry
+}
+
+func (f *fakeSegmentIndexSource) SegmentIndex(partitionKey string) []remote.SegmentIndexEntry {
+	return f.index
+}
+
+func TestSeekerSeekToOffsetResumesPartition(t *testing.T) {
+	rmtDataCache := remote.NewRmtDataCache()
+	resetter := &fakeResetter{}
+	s := newSeeker(rmtDataCache, resetter, &fakeSegmentIndexSource{})
+
+	assert.Nil(t, s.SeekToOffset("p1", 42))
+	assert.Equal(t, "p1", resetter.resetPartitionKey)
+	assert.Equal(t, int64(42), resetter.resetOffset)
+	assert.False(t, rmtDataCache.IsPartitionPaused("p1"))
+}
+
+func TestSeekerLeavesPartitionPausedOnRPCFailure(t *testing.T) {
+	rmtDataCache := remote.NewRmtDataCache()
+	resetter := &fakeResetter{err: errors.New("broker unreachable")}
+	s := newSeeker(rmtDataCache, resetter, &fakeSegmentIndexSource{})
+
+	assert.NotNil(t, s.SeekToOffset("p1", 42))
+	assert.True(t, rmtDataCache.IsPartitionPaused("p1"))
+}
+
+func TestSeekerSeekToTimestampTranslatesViaSegmentIndex(t *testing.T) {
+	rmtDataCache := remote.NewRmtDataCache()
+	resetter := &fakeResetter{}
+	index := &fakeSegmentIndexSource{index: []remote.SegmentIndexEntry{
+		{MaxOffset: 100, UpdateTime: 1000},
+		{MaxOffset: 200, UpdateTime: 2000},
+		{MaxOffset: 300, UpdateTime: 3000},
+	}}
+	s := newSeeker(rmtDataCache, resetter, index)
+
+	assert.Nil(t, s.SeekToTimestamp("p1", 1500))
+	assert.Equal(t, int64(100), resetter.resetOffset)
+}
+
+func TestSeekerSeekToBeginningAndEnd(t *testing.T) {
+	rmtDataCache := remote.NewRmtDataCache()
+	resetter := &fakeResetter{}
+	index := &fakeSegmentIndexSource{index: []remote.SegmentIndexEntry{
+		{MaxOffset: 100, UpdateTime: 1000},
+		{MaxOffset: 300, UpdateTime: 3000},
+	}}
+	s := newSeeker(rmtDataCache, resetter, index)
+
+	assert.Nil(t, s.SeekToBeginning("p1"))
+	assert.Equal(t, int64(0), resetter.resetOffset)
+
+	assert.Nil(t, s.SeekToEnd("p1"))
+	assert.Equal(t, int64(300), resetter.resetOffset)
+}
@@ -25,6 +25,8 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metrics"
 )
 
 const (
@@ -69,8 +71,21 @@ func New(reader io.Reader) *TubeMQDecoder {
 }
 
 // Decode will decode the response from TubeMQ to TransportResponse according to
-// the RPC protocol of TubeMQ.
+// the RPC protocol of TubeMQ, pushing decode error and frame size samples
+// into metrics.Default.
 func (t *TubeMQDecoder) Decode() (TransportResponse, error) {
+	resp, err := t.decode()
+	if err != nil {
+		metrics.Default.IncrCounter(metrics.DecodeErrors, 1)
+		return nil, err
+	}
+	if resp, ok := resp.(TubeMQResponse); ok {
+		metrics.Default.ObserveHistogram(metrics.FrameSize, float64(len(resp.responseBuf)))
+	}
+	return resp, nil
+}
+
+func (t *TubeMQDecoder) decode() (TransportResponse, error) {
 	num, err := io.ReadFull(t.reader, t.msg[:frameHeadLen])
 	if err != nil {
 		return nil, err
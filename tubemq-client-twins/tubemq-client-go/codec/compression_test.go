@@ -0,0 +1,125 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPayload(n int) []byte {
+	b := make([]byte, n)
+	r := rand.New(rand.NewSource(1))
+	for i := range b {
+		// biased towards repeated bytes so the compressors have
+		// something to work with, like a real message batch.
+		b[i] = byte(r.Intn(8))
+	}
+	return b
+}
+
+func TestCompressPayloadRoundTrip(t *testing.T) {
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionLZ4, CompressionZstd} {
+		payload := testPayload(4096)
+		compressed, err := CompressPayload(codec, payload)
+		assert.Nil(t, err)
+
+		decompressed, err := DecompressPayload(compressed)
+		assert.Nil(t, err)
+		assert.True(t, bytes.Equal(payload, decompressed))
+	}
+}
+
+func TestDecompressPayloadUnknownCodec(t *testing.T) {
+	_, err := DecompressPayload([]byte{0xFF, 0x01, 0x02})
+	assert.NotNil(t, err)
+}
+
+func TestDecompressPayloadTooShort(t *testing.T) {
+	_, err := DecompressPayload(nil)
+	assert.NotNil(t, err)
+}
+
+func TestGetCompressorUnknown(t *testing.T) {
+	_, err := GetCompressor(CompressionCodec(99))
+	assert.NotNil(t, err)
+}
+
+func FuzzDecompressPayload(f *testing.F) {
+	f.Add([]byte{byte(CompressionNone)})
+	f.Add([]byte{byte(CompressionSnappy), 0x01, 0x02, 0x03})
+	f.Add([]byte{byte(CompressionLZ4), 0xff, 0xff, 0xff})
+	f.Add([]byte{byte(CompressionZstd), 0x28, 0xb5, 0x2f, 0xfd})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// A malformed compressed frame must be reported as an error,
+		// never panic the decoder.
+		_, _ = DecompressPayload(data)
+	})
+}
+
+func BenchmarkCompressPayload(b *testing.B) {
+	payload := testPayload(64 * 1024)
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionLZ4, CompressionZstd} {
+		b.Run(codecName(codec), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				if _, err := CompressPayload(codec, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecompressPayload(b *testing.B) {
+	payload := testPayload(64 * 1024)
+	for _, codec := range []CompressionCodec{CompressionNone, CompressionSnappy, CompressionLZ4, CompressionZstd} {
+		compressed, err := CompressPayload(codec, payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(codecName(codec), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				if _, err := DecompressPayload(compressed); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func codecName(codec CompressionCodec) string {
+	switch codec {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionLZ4:
+		return "lz4"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
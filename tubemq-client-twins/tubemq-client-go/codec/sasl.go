@@ -0,0 +1,69 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package codec
+
+import "encoding/json"
+
+// OpcodeSASLHandshake is the request opcode for the SASL handshake
+// pre-request. It is exchanged immediately after the TLS handshake and
+// before the client's first heartbeat/register request, so it is carried
+// in the request body's Opcode field rather than requiring a change to the
+// frame header TubeMQDecoder.Decode reads.
+const OpcodeSASLHandshake uint32 = 0x32
+
+// SASLRequest is the body of a SASL handshake pre-request. The initial
+// request in the exchange sets Mechanism and leaves Token nil; every
+// subsequent request answers the previous SASLResponse's challenge by
+// setting Token and leaving Mechanism empty.
+type SASLRequest struct {
+	Opcode    uint32 `json:"opcode"`
+	Mechanism string `json:"mechanism,omitempty"`
+	Token     []byte `json:"token,omitempty"`
+}
+
+// SASLResponse is the body of a SASL handshake response.
+type SASLResponse struct {
+	ErrorCode int32 `json:"errorCode"`
+	// ErrMsg explains ErrorCode; empty when ErrorCode is 0.
+	ErrMsg string `json:"errMsg,omitempty"`
+	// EnabledMechanisms lists the mechanisms the server will accept,
+	// returned in response to the initial handshake request.
+	EnabledMechanisms []string `json:"enabledMechanisms,omitempty"`
+	// Token is the server's next challenge. Once Done is true it instead
+	// carries any final message the mechanism needs to complete the
+	// exchange, e.g. SCRAM's server-final-message with its "v=" signature;
+	// PLAIN leaves it empty.
+	Token []byte `json:"token,omitempty"`
+	// Done reports whether the exchange is complete and the connection
+	// authenticated.
+	Done bool `json:"done"`
+}
+
+// EncodeSASLRequest marshals req to its wire form.
+func EncodeSASLRequest(req *SASLRequest) ([]byte, error) {
+	return json.Marshal(req)
+}
+
+// DecodeSASLResponse unmarshals a SASLResponse from its wire form.
+func DecodeSASLResponse(data []byte) (*SASLResponse, error) {
+	resp := &SASLResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
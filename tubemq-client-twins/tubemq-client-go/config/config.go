@@ -0,0 +1,235 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package config holds the configuration accepted by the TubeMQ Go client,
+// grouped by concern the same way sarama groups its Config: a top-level
+// Config struct made up of independent sections such as Net and Consumer.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/codec"
+)
+
+// RangeAssignor is the range partition assignment strategy: partitions of
+// a topic are divided into contiguous ranges, one per consumer.
+const RangeAssignor = "range"
+
+// RoundRobinAssignor is the round-robin partition assignment strategy:
+// partitions are dealt out to consumers one at a time in turn.
+const RoundRobinAssignor = "roundrobin"
+
+// StickyAssignor is the sticky partition assignment strategy: it behaves
+// like RoundRobinAssignor but minimizes partition movement across
+// rebalances by preferring to keep a consumer's previous assignment.
+const StickyAssignor = "sticky"
+
+// Config is the configuration for the TubeMQ client. It is organized into
+// independent sections, each with its own defaults populated by NewConfig.
+type Config struct {
+	Net       Net
+	Consumer  Consumer
+	Producer  Producer
+	Heartbeat Heartbeat
+}
+
+// Net holds the configuration of the transport connecting to the TubeMQ
+// master and broker.
+type Net struct {
+	TLS struct {
+		// Enable switches the transport to TLS.
+		Enable bool
+	}
+	// SASL configures the authentication handshake performed right after
+	// the TLS handshake and before the client's first heartbeat/register
+	// request.
+	SASL SASL
+}
+
+// SASLMechanism identifies a SASL authentication mechanism the client can
+// negotiate with the broker/master.
+type SASLMechanism string
+
+const (
+	// SASLTypePlain is RFC 4616 SASL PLAIN.
+	SASLTypePlain SASLMechanism = "PLAIN"
+	// SASLTypeSCRAMSHA256 is RFC 5802 SCRAM using SHA-256.
+	SASLTypeSCRAMSHA256 SASLMechanism = "SCRAM-SHA-256"
+	// SASLTypeSCRAMSHA512 is RFC 5802 SCRAM using SHA-512.
+	SASLTypeSCRAMSHA512 SASLMechanism = "SCRAM-SHA-512"
+)
+
+// SASL holds the configuration of the SASL authentication handshake.
+type SASL struct {
+	// Enable turns on the SASL handshake.
+	Enable bool
+	// Mechanism selects the SASL mechanism to authenticate with: one of
+	// SASLTypePlain, SASLTypeSCRAMSHA256 or SASLTypeSCRAMSHA512.
+	Mechanism SASLMechanism
+	// Username is the identity to authenticate as.
+	Username string
+	// Password is the Username's credential.
+	Password string
+}
+
+// Consumer holds the configuration of a TubeMQ consumer.
+type Consumer struct {
+	// Masters is the comma-separated list of master addresses.
+	Masters string
+	// Topics is the list of topics to subscribe to.
+	Topics []string
+	// TopicFilters maps a subscribed topic to the filter attributes the
+	// consumer registers for it, if any.
+	TopicFilters map[string][]string
+	// Group is the consumer group name.
+	Group string
+	// MsgNotFoundWait is how long the consumer waits before retrying a
+	// GetMessage call that came back empty.
+	MsgNotFoundWait time.Duration
+	// PartitionAssignmentStrategy selects how partitions are divided
+	// among the consumers of a group. One of RangeAssignor,
+	// RoundRobinAssignor or StickyAssignor. Defaults to RangeAssignor.
+	PartitionAssignmentStrategy string
+}
+
+// Producer holds the configuration of a TubeMQ producer.
+type Producer struct {
+	// CompressionType is the codec.CompressionCodec batches at least
+	// CompressionMinSize bytes are compressed with before being sent to
+	// the broker. Defaults to codec.CompressionNone.
+	CompressionType codec.CompressionCodec
+	// CompressionMinSize is the minimum batch size, in bytes, a producer
+	// will compress. Batches smaller than this are sent uncompressed,
+	// since compression overhead can outweigh its benefit on them.
+	CompressionMinSize int
+}
+
+// SetCompressionType sets the codec batches of at least minSize bytes are
+// compressed with before being sent to the broker. Pass codec.CompressionNone
+// to disable compression.
+func (p *Producer) SetCompressionType(compressionType codec.CompressionCodec, minSize int) {
+	p.CompressionType = compressionType
+	p.CompressionMinSize = minSize
+}
+
+// Heartbeat holds the configuration of the periodic heartbeat a consumer
+// sends to its master and brokers.
+type Heartbeat struct {
+	// MaxRetryTimes is the number of consecutive heartbeat failures the
+	// client tolerates before treating the connection as lost.
+	MaxRetryTimes int
+}
+
+// NewConfig returns a Config populated with the client's defaults.
+func NewConfig() *Config {
+	c := &Config{}
+	c.Consumer.MsgNotFoundWait = 400 * time.Millisecond
+	c.Consumer.PartitionAssignmentStrategy = RangeAssignor
+	c.Producer.CompressionMinSize = 4096
+	c.Heartbeat.MaxRetryTimes = 5
+	return c
+}
+
+// ParseAddress parses a TubeMQ client address of the form
+// "host1:port1,host2:port2?key=value&...", the format accepted by
+// NewConsumer, into a Config built from NewConfig's defaults. Recognized
+// query parameters are:
+//
+//	topics                  semicolon-separated topic@filter1,filter2 list
+//	group                   consumer group name
+//	tlsEnable               enables TLS on the transport
+//	msgNotFoundWait         milliseconds to wait after a NOT_FOUND response
+//	heartbeatMaxRetryTimes  max consecutive heartbeat failures tolerated
+func ParseAddress(address string) (*Config, error) {
+	if address == "" {
+		return nil, errors.New("config: address must not be empty")
+	}
+	masters := address
+	query := ""
+	if idx := strings.Index(address, "?"); idx >= 0 {
+		masters = address[:idx]
+		query = address[idx+1:]
+	}
+
+	c := NewConfig()
+	c.Consumer.Masters = masters
+	if query != "" {
+		if err := parseQuery(c, query); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseQuery(c *Config, query string) error {
+	for _, param := range strings.Split(query, "&") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("config: invalid query parameter %q", param)
+		}
+		key, value := kv[0], kv[1]
+		var err error
+		switch key {
+		case "topics":
+			c.Consumer.Topics, c.Consumer.TopicFilters, err = parseTopics(value)
+		case "group":
+			c.Consumer.Group = value
+		case "tlsEnable":
+			c.Net.TLS.Enable, err = strconv.ParseBool(value)
+		case "msgNotFoundWait":
+			var ms int
+			if ms, err = strconv.Atoi(value); err == nil {
+				c.Consumer.MsgNotFoundWait = time.Duration(ms) * time.Millisecond
+			}
+		case "heartbeatMaxRetryTimes":
+			c.Heartbeat.MaxRetryTimes, err = strconv.Atoi(value)
+		default:
+			return fmt.Errorf("config: unknown query parameter %q", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTopics parses a "Topic1@filter1,filter2;Topic2" value into an
+// ordered topic list and a topic -> filter attributes map.
+func parseTopics(value string) ([]string, map[string][]string, error) {
+	topics := make([]string, 0)
+	filters := make(map[string][]string)
+	for _, entry := range strings.Split(value, ";") {
+		if entry == "" {
+			continue
+		}
+		name := entry
+		if idx := strings.Index(entry, "@"); idx >= 0 {
+			name = entry[:idx]
+			filters[name] = strings.Split(entry[idx+1:], ",")
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("config: invalid topic entry %q", entry)
+		}
+		topics = append(topics, name)
+	}
+	return topics, filters, nil
+}
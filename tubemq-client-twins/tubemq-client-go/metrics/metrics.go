@@ -0,0 +1,187 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics collects operational counters and histograms from the
+// TubeMQ client's internals (RmtDataCache and codec's TubeMQDecoder) so
+// that consumers operating at scale can observe them without pulling in a
+// specific metrics backend. No heartbeat metrics are collected. The in-process Snapshot API has no external
+// dependency; a prometheus.Collector adapter is available behind the
+// "prometheus" build tag for users who want to scrape /metrics.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter names pushed into the default Registry.
+const (
+	EventsOffered     = "events_offered"
+	EventsTaken       = "events_taken"
+	PartitionsAdded   = "partitions_added"
+	PartitionsRemoved = "partitions_removed"
+	PartitionsExpired = "partitions_expired"
+	DecodeErrors      = "decode_errors"
+)
+
+// Histogram names pushed into the default Registry.
+const (
+	PartitionIdleDuration = "partition_idle_duration_ms"
+	FrameSize             = "frame_size_bytes"
+)
+
+// defaultHistogramBuckets are the upper bounds, in the unit of the
+// histogram being recorded to, of the buckets every Histogram is created
+// with.
+var defaultHistogramBuckets = []float64{10, 50, 100, 500, 1000, 5000, 10000, 50000, 100000}
+
+// Registry holds the counters and histograms the client pushes samples
+// into, each created on the first sample pushed under its name. Use the
+// package-level Default registry unless a test needs an isolated one.
+type Registry struct {
+	mu         sync.Mutex
+	counters   map[string]*int64
+	histograms map[string]*Histogram
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		counters:   make(map[string]*int64),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Default is the Registry the client's internals push samples into unless
+// configured otherwise.
+var Default = NewRegistry()
+
+// IncrCounter increments the named counter by delta, creating it if this is
+// the first sample pushed under that name.
+func (r *Registry) IncrCounter(name string, delta int64) {
+	r.mu.Lock()
+	counter, ok := r.counters[name]
+	if !ok {
+		counter = new(int64)
+		r.counters[name] = counter
+	}
+	r.mu.Unlock()
+	atomic.AddInt64(counter, delta)
+}
+
+// ObserveHistogram records value under the named histogram, creating it
+// with the package's default buckets if this is the first sample pushed
+// under that name.
+func (r *Registry) ObserveHistogram(name string, value float64) {
+	r.mu.Lock()
+	histogram, ok := r.histograms[name]
+	if !ok {
+		histogram = NewHistogram(defaultHistogramBuckets)
+		r.histograms[name] = histogram
+	}
+	r.mu.Unlock()
+	histogram.Observe(value)
+}
+
+// Snapshot is a point-in-time copy of every counter and histogram a
+// Registry holds.
+type Snapshot struct {
+	Counters   map[string]int64
+	Histograms map[string]HistogramSnapshot
+}
+
+// Snapshot returns a point-in-time copy of every counter and histogram
+// currently registered.
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := Snapshot{
+		Counters:   make(map[string]int64, len(r.counters)),
+		Histograms: make(map[string]HistogramSnapshot, len(r.histograms)),
+	}
+	for name, counter := range r.counters {
+		snapshot.Counters[name] = atomic.LoadInt64(counter)
+	}
+	for name, histogram := range r.histograms {
+		snapshot.Histograms[name] = histogram.Snapshot()
+	}
+	return snapshot
+}
+
+// Histogram is a simple fixed-bucket histogram that counts each observation
+// in a single bucket. It is safe for concurrent use.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []int64
+	sum     float64
+	count   int64
+}
+
+// NewHistogram returns a Histogram with the given bucket upper bounds,
+// which need not be sorted; NewHistogram sorts and deduplicates them.
+func NewHistogram(buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	deduped := make([]float64, 0, len(sorted))
+	for _, bound := range sorted {
+		if len(deduped) == 0 || bound != deduped[len(deduped)-1] {
+			deduped = append(deduped, bound)
+		}
+	}
+	return &Histogram{
+		buckets: deduped,
+		counts:  make([]int64, len(deduped)+1),
+	}
+}
+
+// Observe records value into the bucket of the smallest upper bound that is
+// >= value, or the overflow bucket if value exceeds every bound.
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	idx := sort.SearchFloat64s(h.buckets, value)
+	h.counts[idx]++
+	h.sum += value
+	h.count++
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram's state.
+type HistogramSnapshot struct {
+	// Buckets are the configured upper bounds, ascending.
+	Buckets []float64
+	// Counts holds, for each index i < len(Buckets), the number of
+	// observations <= Buckets[i] and > Buckets[i-1] (if any); the final,
+	// extra element is the count of observations exceeding every bound.
+	// Counts are per bucket, not cumulative.
+	Counts []int64
+	Sum    float64
+	Count  int64
+}
+
+// Snapshot returns a point-in-time copy of the histogram's state.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return HistogramSnapshot{
+		Buckets: append([]float64(nil), h.buckets...),
+		Counts:  append([]int64(nil), h.counts...),
+		Sum:     h.sum,
+		Count:   h.count,
+	}
+}
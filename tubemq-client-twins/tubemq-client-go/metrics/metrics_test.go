@@ -0,0 +1,61 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryIncrCounter(t *testing.T) {
+	r := NewRegistry()
+	r.IncrCounter(EventsOffered, 1)
+	r.IncrCounter(EventsOffered, 2)
+	r.IncrCounter(EventsTaken, 1)
+
+	snapshot := r.Snapshot()
+	assert.Equal(t, int64(3), snapshot.Counters[EventsOffered])
+	assert.Equal(t, int64(1), snapshot.Counters[EventsTaken])
+	assert.Equal(t, int64(0), snapshot.Counters[PartitionsAdded])
+}
+
+func TestRegistryObserveHistogram(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveHistogram(FrameSize, 5)
+	r.ObserveHistogram(FrameSize, 60)
+	r.ObserveHistogram(FrameSize, 200000)
+
+	snapshot := r.Snapshot().Histograms[FrameSize]
+	assert.Equal(t, int64(3), snapshot.Count)
+	assert.Equal(t, float64(200065), snapshot.Sum)
+	// 5 falls in the first (<=10) bucket, 60 in the (<=100) bucket, and
+	// 200000 overflows every configured bucket into the extra, final one.
+	assert.Equal(t, int64(1), snapshot.Counts[0])
+	assert.Equal(t, int64(1), snapshot.Counts[2])
+	assert.Equal(t, int64(1), snapshot.Counts[len(snapshot.Counts)-1])
+}
+
+func TestHistogramSnapshotIsIndependentCopy(t *testing.T) {
+	h := NewHistogram([]float64{10, 100})
+	h.Observe(5)
+	snapshot := h.Snapshot()
+	h.Observe(5)
+	assert.Equal(t, int64(1), snapshot.Count)
+	assert.Equal(t, int64(2), h.Snapshot().Count)
+}
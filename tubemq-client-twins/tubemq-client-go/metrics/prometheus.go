@@ -0,0 +1,66 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:build prometheus
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace is the prometheus metric namespace every collected metric is
+// published under, e.g. tubemq_client_events_offered.
+const namespace = "tubemq_client"
+
+// Collector adapts a Registry to prometheus.Collector, so it can be
+// registered with a prometheus.Registerer and scraped over /metrics. It is
+// only built when the "prometheus" build tag is set, so the default build
+// does not pull in the prometheus client as a dependency.
+type Collector struct {
+	registry *Registry
+}
+
+// NewCollector returns a prometheus.Collector publishing every counter and
+// histogram in registry.
+func NewCollector(registry *Registry) *Collector {
+	return &Collector{registry: registry}
+}
+
+// Describe implements prometheus.Collector. The metric set is dynamic, so
+// Describe intentionally sends no descriptors, making this an "unchecked"
+// collector as documented by prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.registry.Snapshot()
+	for name, value := range snapshot.Counters {
+		desc := prometheus.NewDesc(namespace+"_"+name, "TubeMQ client counter "+name, nil, nil)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value))
+	}
+	for name, histogram := range snapshot.Histograms {
+		desc := prometheus.NewDesc(namespace+"_"+name, "TubeMQ client histogram "+name, nil, nil)
+		buckets := make(map[float64]uint64, len(histogram.Buckets))
+		cumulative := uint64(0)
+		for i, bound := range histogram.Buckets {
+			cumulative += uint64(histogram.Counts[i])
+			buckets[bound] = cumulative
+		}
+		ch <- prometheus.MustNewConstHistogram(desc, uint64(histogram.Count), histogram.Sum, buckets)
+	}
+}
@@ -0,0 +1,196 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/util"
+)
+
+// flowCtrlType identifies which of the rule sets a flowCtrlRuleJSON entry
+// belongs to, as sent by the TubeMQ master/broker.
+type flowCtrlType int32
+
+const (
+	// flowCtrlFrequency gates how often a consumer may issue GetMessage
+	// requests, optionally alongside a qryPriorityID override.
+	flowCtrlFrequency flowCtrlType = 0
+	// flowCtrlThroughput limits the bytes/messages a consumer may pull
+	// during a given hour-of-day window.
+	flowCtrlThroughput flowCtrlType = 1
+	// flowCtrlSSDTransfer slows a consumer down once it falls more than
+	// a threshold behind the partition's max offset, so the broker can
+	// serve it off of SSD rather than the hot in-memory segment.
+	flowCtrlSSDTransfer flowCtrlType = 2
+	// flowCtrlFilterFrequency gates the request frequency of consumers
+	// that subscribe with a server-side filter.
+	flowCtrlFilterFrequency flowCtrlType = 3
+)
+
+// flowCtrlRuleJSON mirrors one `{"type":.., "rule":[...]}` entry of the
+// flow-control rule set pushed down by the master in heartbeat/register
+// responses.
+type flowCtrlRuleJSON struct {
+	Type int32                `json:"type"`
+	Rule []flowCtrlSubRuleJSON `json:"rule"`
+}
+
+// flowCtrlSubRuleJSON is a single sub-rule of a flowCtrlRuleJSON entry. Not
+// every field is meaningful for every Type; see flowCtrlType.
+type flowCtrlSubRuleJSON struct {
+	StartTime     int32 `json:"start"`
+	EndTime       int32 `json:"end"`
+	DltInSz       int64 `json:"dltInSz"`
+	DltInMs       int64 `json:"dltInMs"`
+	QryPriorityID int32 `json:"qryPriorityID"`
+	FreqMs        int64 `json:"freqMs"`
+}
+
+// flowCtrlRule is the parsed, in-memory form of a flowCtrlSubRuleJSON.
+type flowCtrlRule struct {
+	startHour int32
+	endHour   int32
+	dltInSize int64
+	dltInMs   int64
+	freqMs    int64
+}
+
+// inHourRange reports whether hour falls within [startHour, endHour),
+// treating startHour > endHour as an overnight window, e.g. [22, 6).
+func (r *flowCtrlRule) inHourRange(hour int32) bool {
+	if r.startHour == r.endHour {
+		return true
+	}
+	if r.startHour < r.endHour {
+		return hour >= r.startHour && hour < r.endHour
+	}
+	return hour >= r.startHour || hour < r.endHour
+}
+
+// flowCtrlRuleHandler parses and evaluates the flow-control rules TubeMQ
+// sends for a single flowCtrlID, either the cluster-wide default or a
+// specific consumer group. It is safe for concurrent use.
+type flowCtrlRuleHandler struct {
+	mu            sync.RWMutex
+	flowCtrlID    int64
+	qryPriorityID int32
+	rules         map[flowCtrlType][]*flowCtrlRule
+}
+
+// newFlowCtrlRuleHandler returns a handler with no rules configured.
+func newFlowCtrlRuleHandler() *flowCtrlRuleHandler {
+	return &flowCtrlRuleHandler{
+		flowCtrlID:    util.InvalidValue,
+		qryPriorityID: int32(util.InvalidValue),
+		rules:         make(map[flowCtrlType][]*flowCtrlRule),
+	}
+}
+
+// update replaces the handler's rule set with the rules encoded in
+// flowCtrlInfo, evicting the previously parsed rules. It is a no-op when
+// flowCtrlID matches the rules already held, since the master resends the
+// same flowCtrlID on every heartbeat until the rules actually change.
+func (h *flowCtrlRuleHandler) update(flowCtrlID int64, qryPriorityID int32, flowCtrlInfo string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if flowCtrlID == h.flowCtrlID {
+		return nil
+	}
+	rules := make(map[flowCtrlType][]*flowCtrlRule)
+	if len(flowCtrlInfo) > 0 && flowCtrlInfo != "[]" {
+		var entries []flowCtrlRuleJSON
+		if err := json.Unmarshal([]byte(flowCtrlInfo), &entries); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			t := flowCtrlType(entry.Type)
+			for _, sub := range entry.Rule {
+				rules[t] = append(rules[t], &flowCtrlRule{
+					startHour: sub.StartTime,
+					endHour:   sub.EndTime,
+					dltInSize: sub.DltInSz,
+					dltInMs:   sub.DltInMs,
+					freqMs:    sub.FreqMs,
+				})
+				if t == flowCtrlFrequency && sub.QryPriorityID != 0 {
+					qryPriorityID = sub.QryPriorityID
+				}
+			}
+		}
+	}
+	h.flowCtrlID = flowCtrlID
+	h.rules = rules
+	if qryPriorityID != int32(util.InvalidValue) {
+		h.qryPriorityID = qryPriorityID
+	}
+	return nil
+}
+
+// getQryPriorityID returns the qryPriorityID carried by the handler's
+// current rule set.
+func (h *flowCtrlRuleHandler) getQryPriorityID() int32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.qryPriorityID
+}
+
+// getCurConsumeLimit evaluates the handler's throughput, SSD-transfer and
+// frequency rules for the given time of day and consumption lag, returning
+// the byte limit for the next pull (util.InvalidValue if unrestricted), the
+// minimum delay to wait before issuing it, and whether the caller is far
+// enough behind that it should slow down regardless of the configured
+// frequency. nowMinute is the number of minutes elapsed since midnight;
+// dltFromMax is how far, in bytes, the consumer trails the partition's max
+// offset.
+func (h *flowCtrlRuleHandler) getCurConsumeLimit(nowMinute int32, dltFromMax int64) (limitBytes int32, freqMs int64, needSlowDown bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	limitBytes = int32(util.InvalidValue)
+	nowHour := (nowMinute / 60) % 24
+	for _, rule := range h.rules[flowCtrlThroughput] {
+		if rule.inHourRange(nowHour) {
+			if rule.dltInSize >= 0 && (limitBytes == int32(util.InvalidValue) || int32(rule.dltInSize) < limitBytes) {
+				limitBytes = int32(rule.dltInSize)
+			}
+			if rule.dltInMs > freqMs {
+				freqMs = rule.dltInMs
+			}
+		}
+	}
+	for _, rule := range h.rules[flowCtrlSSDTransfer] {
+		if rule.inHourRange(nowHour) && dltFromMax > rule.dltInSize {
+			needSlowDown = true
+			if rule.freqMs > freqMs {
+				freqMs = rule.freqMs
+			}
+		}
+	}
+	for _, rule := range h.rules[flowCtrlFrequency] {
+		if rule.freqMs > freqMs {
+			freqMs = rule.freqMs
+		}
+	}
+	for _, rule := range h.rules[flowCtrlFilterFrequency] {
+		if rule.freqMs > freqMs {
+			freqMs = rule.freqMs
+		}
+	}
+	return limitBytes, freqMs, needSlowDown
+}
@@ -0,0 +1,93 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowCtrlRuleHandlerHourBoundary(t *testing.T) {
+	h := newFlowCtrlRuleHandler()
+	// a throughput rule active from 22:00 to 06:00, overnight.
+	rules := `[{"type":1,"rule":[{"start":22,"end":6,"dltInSz":1024,"dltInMs":300}]}]`
+	err := h.update(1, int32(util.InvalidValue), rules)
+	assert.Nil(t, err)
+
+	limitBytes, freqMs, needSlowDown := h.getCurConsumeLimit(23*60, 0)
+	assert.Equal(t, int32(1024), limitBytes)
+	assert.Equal(t, int64(300), freqMs)
+	assert.False(t, needSlowDown)
+
+	limitBytes, freqMs, needSlowDown = h.getCurConsumeLimit(5*60+59, 0)
+	assert.Equal(t, int32(1024), limitBytes)
+	assert.Equal(t, int64(300), freqMs)
+
+	limitBytes, _, _ = h.getCurConsumeLimit(6*60, 0)
+	assert.Equal(t, int32(util.InvalidValue), limitBytes)
+
+	limitBytes, _, _ = h.getCurConsumeLimit(21*60+59, 0)
+	assert.Equal(t, int32(util.InvalidValue), limitBytes)
+}
+
+func TestFlowCtrlRuleHandlerMultiRuleOverlap(t *testing.T) {
+	h := newFlowCtrlRuleHandler()
+	rules := `[
+		{"type":1,"rule":[{"start":0,"end":24,"dltInSz":2048,"dltInMs":100}]},
+		{"type":2,"rule":[{"start":0,"end":24,"dltInSz":100,"freqMs":500}]},
+		{"type":0,"rule":[{"freqMs":50}]}
+	]`
+	err := h.update(2, int32(util.InvalidValue), rules)
+	assert.Nil(t, err)
+
+	limitBytes, freqMs, needSlowDown := h.getCurConsumeLimit(10*60, 50)
+	assert.Equal(t, int32(2048), limitBytes)
+	assert.False(t, needSlowDown)
+	assert.Equal(t, int64(100), freqMs)
+
+	limitBytes, freqMs, needSlowDown = h.getCurConsumeLimit(10*60, 200)
+	assert.Equal(t, int32(2048), limitBytes)
+	assert.True(t, needSlowDown)
+	assert.Equal(t, int64(500), freqMs)
+}
+
+func TestFlowCtrlRuleHandlerSkipsUnchangedFlowCtrlID(t *testing.T) {
+	h := newFlowCtrlRuleHandler()
+	assert.Nil(t, h.update(3, int32(util.InvalidValue), `[{"type":1,"rule":[{"start":0,"end":24,"dltInSz":10}]}]`))
+	// A malformed payload for the same flowCtrlID must not clobber the
+	// existing rules, since the master sends changed rules only under a
+	// new flowCtrlID.
+	assert.Nil(t, h.update(3, int32(util.InvalidValue), `not json`))
+	limitBytes, _, _ := h.getCurConsumeLimit(0, 0)
+	assert.Equal(t, int32(10), limitBytes)
+}
+
+func TestRmtDataCacheGetCurConsumeLimitMergesDefAndGroup(t *testing.T) {
+	r := NewRmtDataCache()
+	r.UpdateDefFlowCtrlInfo(1, `[{"type":1,"rule":[{"start":0,"end":24,"dltInSz":4096,"dltInMs":100}]}]`)
+	r.UpdateGroupFlowCtrlInfo(7, 2, `[{"type":1,"rule":[{"start":0,"end":24,"dltInSz":1024,"dltInMs":200}]}]`)
+
+	limitBytes, freqMs, needSlowDown := r.GetCurConsumeLimit(0, 0)
+	assert.Equal(t, int32(1024), limitBytes)
+	assert.Equal(t, int64(200), freqMs)
+	assert.False(t, needSlowDown)
+	assert.True(t, r.GetUnderGroupCtrl())
+	assert.Equal(t, int32(7), r.GetQryPriorityID())
+}
@@ -0,0 +1,56 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"sort"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/util"
+)
+
+// SegmentIndexEntry is one entry of a partition's segment index: the
+// highest offset stored in that segment, and the wall-clock time, in epoch
+// milliseconds, at which the segment was last updated.
+type SegmentIndexEntry struct {
+	MaxOffset  int64
+	UpdateTime int64
+}
+
+// SearchOffsetByTimestamp translates tsMillis into an offset by binary
+// searching index, which must be sorted ascending by UpdateTime (and
+// therefore by MaxOffset, since later segments hold higher offsets). The
+// segment matching tsMillis holds messages from the previous segment's
+// MaxOffset (exclusive) onward, so SearchOffsetByTimestamp returns that
+// floor rather than the matched segment's own MaxOffset, which would skip
+// straight to its last message. It returns the last segment's MaxOffset if
+// every segment predates tsMillis, or util.InvalidValue if index is empty.
+func SearchOffsetByTimestamp(index []SegmentIndexEntry, tsMillis int64) int64 {
+	if len(index) == 0 {
+		return util.InvalidValue
+	}
+	i := sort.Search(len(index), func(i int) bool {
+		return index[i].UpdateTime >= tsMillis
+	})
+	if i == len(index) {
+		return index[len(index)-1].MaxOffset
+	}
+	if i == 0 {
+		return 0
+	}
+	return index[i-1].MaxOffset
+}
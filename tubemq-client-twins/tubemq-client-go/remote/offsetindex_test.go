@@ -0,0 +1,54 @@
+/**
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ * <p>
+ * http://www.apache.org/licenses/LICENSE-2.0
+ * <p>
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchOffsetByTimestamp(t *testing.T) {
+	index := []SegmentIndexEntry{
+		{MaxOffset: 100, UpdateTime: 1000},
+		{MaxOffset: 200, UpdateTime: 2000},
+		{MaxOffset: 300, UpdateTime: 3000},
+	}
+
+	assert.Equal(t, int64(0), SearchOffsetByTimestamp(index, 500))
+	assert.Equal(t, int64(0), SearchOffsetByTimestamp(index, 1000))
+	assert.Equal(t, int64(100), SearchOffsetByTimestamp(index, 1500))
+	assert.Equal(t, int64(300), SearchOffsetByTimestamp(index, 3500))
+	assert.Equal(t, int64(util.InvalidValue), SearchOffsetByTimestamp(nil, 1000))
+}
+
+func TestPauseAndResumePartition(t *testing.T) {
+	r := NewRmtDataCache()
+	p := &metadata.Partition{}
+	p.SetPartitionKey("p1")
+	r.AddNewPartition(p)
+
+	assert.False(t, r.IsPartitionPaused("p1"))
+	r.PausePartition("p1")
+	assert.True(t, r.IsPartitionPaused("p1"))
+
+	r.ResumePartition("p1")
+	assert.False(t, r.IsPartitionPaused("p1"))
+}
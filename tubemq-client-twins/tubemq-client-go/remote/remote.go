@@ -23,49 +23,63 @@ import (
 	"time"
 
 	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metadata"
+	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/metrics"
 	"github.com/apache/incubator-inlong/tubemq-client-twins/tubemq-client-go/util"
 )
 
 // RmtDataCache represents the data returned from TubeMQ.
 type RmtDataCache struct {
-	consumerID         string
-	groupName          string
-	underGroupCtrl     bool
-	defFlowCtrlID      int64
-	groupFlowCtrlID    int64
-	partitionSubInfo   map[string]*metadata.SubscribeInfo
-	rebalanceResults   []*metadata.ConsumerEvent
-	eventMu            sync.Mutex
-	metaMu             sync.Mutex
-	dataBookMu         sync.Mutex
-	brokerPartitions   map[*metadata.Node]map[string]bool
-	qryPriorityID      int32
-	partitions         map[string]*metadata.Partition
-	usedPartitions     map[string]int64
-	indexPartitions    map[string]bool
-	partitionTimeouts  map[string]*time.Timer
-	topicPartitions    map[string]map[string]bool
-	partitionRegBooked map[string]bool
+	consumerID           string
+	groupName            string
+	underGroupCtrl       bool
+	defFlowCtrlID        int64
+	groupFlowCtrlID      int64
+	defFlowCtrlHandler   *flowCtrlRuleHandler
+	groupFlowCtrlHandler *flowCtrlRuleHandler
+	partitionSubInfo     map[string]*metadata.SubscribeInfo
+	rebalanceResults     []*metadata.ConsumerEvent
+	eventMu              sync.Mutex
+	metaMu               sync.Mutex
+	dataBookMu           sync.Mutex
+	brokerPartitions     map[*metadata.Node]map[string]bool
+	qryPriorityID        int32
+	partitions           map[string]*metadata.Partition
+	usedPartitions       map[string]int64
+	indexPartitions      map[string]bool
+	partitionTimeouts    map[string]*time.Timer
+	topicPartitions      map[string]map[string]bool
+	partitionRegBooked   map[string]bool
+	partitionIdleSince   map[string]int64
+	pausedPartitions     map[string]bool
 }
 
 // NewRmtDataCache returns a default rmtDataCache.
 func NewRmtDataCache() *RmtDataCache {
 	return &RmtDataCache{
-		defFlowCtrlID:      util.InvalidValue,
-		groupFlowCtrlID:    util.InvalidValue,
-		qryPriorityID:      int32(util.InvalidValue),
-		partitionSubInfo:   make(map[string]*metadata.SubscribeInfo),
-		rebalanceResults:   make([]*metadata.ConsumerEvent, 0, 0),
-		brokerPartitions:   make(map[*metadata.Node]map[string]bool),
-		partitions:         make(map[string]*metadata.Partition),
-		usedPartitions:     make(map[string]int64),
-		indexPartitions:    make(map[string]bool),
-		partitionTimeouts:  make(map[string]*time.Timer),
-		topicPartitions:    make(map[string]map[string]bool),
-		partitionRegBooked: make(map[string]bool),
+		defFlowCtrlID:        util.InvalidValue,
+		groupFlowCtrlID:      util.InvalidValue,
+		defFlowCtrlHandler:   newFlowCtrlRuleHandler(),
+		groupFlowCtrlHandler: newFlowCtrlRuleHandler(),
+		qryPriorityID:        int32(util.InvalidValue),
+		partitionSubInfo:     make(map[string]*metadata.SubscribeInfo),
+		rebalanceResults:     make([]*metadata.ConsumerEvent, 0, 0),
+		brokerPartitions:     make(map[*metadata.Node]map[string]bool),
+		partitions:           make(map[string]*metadata.Partition),
+		usedPartitions:       make(map[string]int64),
+		indexPartitions:      make(map[string]bool),
+		partitionTimeouts:    make(map[string]*time.Timer),
+		topicPartitions:      make(map[string]map[string]bool),
+		partitionRegBooked:   make(map[string]bool),
+		partitionIdleSince:   make(map[string]int64),
+		pausedPartitions:     make(map[string]bool),
 	}
 }
 
+// nowMs returns the current time in epoch milliseconds.
+func nowMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
 // GetUnderGroupCtrl returns the underGroupCtrl.
 func (r *RmtDataCache) GetUnderGroupCtrl() bool {
 	return r.underGroupCtrl
@@ -135,14 +149,55 @@ func (r *RmtDataCache) SetConsumerInfo(consumerID string, group string) {
 	r.groupName = group
 }
 
-// UpdateDefFlowCtrlInfo updates the defFlowCtrlInfo.
+// UpdateDefFlowCtrlInfo updates the cluster-wide default flow-control rules,
+// keyed by flowCtrlID, and evicts the previously parsed rules if the server
+// has issued a new flowCtrlID. flowCtrlInfo carries no rules for the caller
+// to parse if flowCtrlID has not changed since the last call.
 func (r *RmtDataCache) UpdateDefFlowCtrlInfo(flowCtrlID int64, flowCtrlInfo string) {
-
+	if err := r.defFlowCtrlHandler.update(flowCtrlID, int32(util.InvalidValue), flowCtrlInfo); err != nil {
+		return
+	}
+	r.defFlowCtrlID = flowCtrlID
 }
 
-// UpdateGroupFlowCtrlInfo updates the groupFlowCtrlInfo.
+// UpdateGroupFlowCtrlInfo updates the flow-control rules of the consumer's
+// group, keyed by flowCtrlID, and evicts the previously parsed rules if the
+// server has issued a new flowCtrlID.
 func (r *RmtDataCache) UpdateGroupFlowCtrlInfo(qryPriorityID int32, flowCtrlID int64, flowCtrlInfo string) {
+	if err := r.groupFlowCtrlHandler.update(flowCtrlID, qryPriorityID, flowCtrlInfo); err != nil {
+		return
+	}
+	r.groupFlowCtrlID = flowCtrlID
+	r.underGroupCtrl = flowCtrlID != util.InvalidValue
+	r.qryPriorityID = r.groupFlowCtrlHandler.getQryPriorityID()
+}
 
+// GetCurConsumeLimit merges the group and cluster-default flow-control rules
+// for the current time of day and consumption lag, and returns the byte
+// limit to apply to the next GetMessage call (util.InvalidValue if none
+// applies), the minimum delay to sleep before issuing it, and whether the
+// caller should back off regardless of that delay. nowMinute is the number
+// of minutes elapsed since midnight; dltFromMax is how far, in bytes, the
+// consumer trails the partition's max offset. Callers should sleep freqMs
+// before their next GetMessage RPC whenever it is non-zero.
+//
+// Nothing in this tree calls it yet: there is no client/consumer_impl.go or
+// heartbeat loop for it to gate GetMessage calls from. Wiring it in belongs
+// with whichever change adds that loop.
+func (r *RmtDataCache) GetCurConsumeLimit(nowMinute int32, dltFromMax int64) (limitBytes int32, freqMs int64, needSlowDown bool) {
+	defLimit, defFreq, defSlow := r.defFlowCtrlHandler.getCurConsumeLimit(nowMinute, dltFromMax)
+	groupLimit, groupFreq, groupSlow := r.groupFlowCtrlHandler.getCurConsumeLimit(nowMinute, dltFromMax)
+
+	limitBytes = defLimit
+	if groupLimit != int32(util.InvalidValue) && (limitBytes == int32(util.InvalidValue) || groupLimit < limitBytes) {
+		limitBytes = groupLimit
+	}
+	freqMs = defFreq
+	if groupFreq > freqMs {
+		freqMs = groupFreq
+	}
+	needSlowDown = defSlow || groupSlow
+	return limitBytes, freqMs, needSlowDown
 }
 
 // OfferEvent offers an consumer event.
@@ -150,6 +205,7 @@ func (r *RmtDataCache) OfferEvent(event *metadata.ConsumerEvent) {
 	r.eventMu.Lock()
 	defer r.eventMu.Unlock()
 	r.rebalanceResults = append(r.rebalanceResults, event)
+	metrics.Default.IncrCounter(metrics.EventsOffered, 1)
 }
 
 // TakeEvent takes an event from the rebalanceResults.
@@ -161,6 +217,7 @@ func (r *RmtDataCache) TakeEvent() *metadata.ConsumerEvent {
 	}
 	event := r.rebalanceResults[0]
 	r.rebalanceResults = r.rebalanceResults[1:]
+	metrics.Default.IncrCounter(metrics.EventsTaken, 1)
 	return event
 }
 
@@ -215,6 +272,7 @@ func (r *RmtDataCache) removeMetaInfo(partitionKey string) {
 		}
 		delete(r.partitions, partitionKey)
 		delete(r.partitionSubInfo, partitionKey)
+		metrics.Default.IncrCounter(metrics.PartitionsRemoved, 1)
 	}
 }
 
@@ -227,14 +285,46 @@ func (r *RmtDataCache) resetIdlePartition(partitionKey string, reuse bool) {
 		timer.Stop()
 		delete(r.partitionTimeouts, partitionKey)
 	}
+	if idleSince, ok := r.partitionIdleSince[partitionKey]; ok {
+		metrics.Default.ObserveHistogram(metrics.PartitionIdleDuration, float64(nowMs()-idleSince))
+		delete(r.partitionIdleSince, partitionKey)
+	}
 	delete(r.indexPartitions, partitionKey)
 	if reuse {
-		if _, ok := r.partitions[partitionKey]; ok {
+		if _, ok := r.partitions[partitionKey]; ok && !r.pausedPartitions[partitionKey] {
 			r.indexPartitions[partitionKey] = true
+			r.partitionIdleSince[partitionKey] = nowMs()
 		}
 	}
 }
 
+// PausePartition takes partitionKey out of consideration for GetMessage
+// selection without forgetting its subscription, so that it can be resumed
+// later with the same broker registration. Used to take a partition
+// offline while its offset is being reset on the broker.
+func (r *RmtDataCache) PausePartition(partitionKey string) {
+	r.metaMu.Lock()
+	defer r.metaMu.Unlock()
+	r.pausedPartitions[partitionKey] = true
+	r.resetIdlePartition(partitionKey, false)
+}
+
+// ResumePartition undoes a prior PausePartition, making partitionKey
+// eligible for GetMessage selection again.
+func (r *RmtDataCache) ResumePartition(partitionKey string) {
+	r.metaMu.Lock()
+	defer r.metaMu.Unlock()
+	delete(r.pausedPartitions, partitionKey)
+	r.resetIdlePartition(partitionKey, true)
+}
+
+// IsPartitionPaused reports whether partitionKey is currently paused.
+func (r *RmtDataCache) IsPartitionPaused(partitionKey string) bool {
+	r.metaMu.Lock()
+	defer r.metaMu.Unlock()
+	return r.pausedPartitions[partitionKey]
+}
+
 // FilterPartitions returns the unsubscribed partitions.
 func (r *RmtDataCache) FilterPartitions(subInfos []*metadata.SubscribeInfo) []*metadata.Partition {
 	r.metaMu.Lock()
@@ -281,6 +371,7 @@ func (r *RmtDataCache) AddNewPartition(newPartition *metadata.Partition) {
 			partitions[partitionKey] = true
 		}
 		r.partitionSubInfo[partitionKey] = sub
+		metrics.Default.IncrCounter(metrics.PartitionsAdded, 1)
 	}
 	r.resetIdlePartition(partitionKey, true)
 }
@@ -303,6 +394,7 @@ func (r *RmtDataCache) HandleExpiredPartitions(wait time.Duration) {
 		if len(expired) > 0 {
 			for partition := range expired {
 				r.resetIdlePartition(partition, true)
+				metrics.Default.IncrCounter(metrics.PartitionsExpired, 1)
 			}
 		}
 	}